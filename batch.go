@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NikolayBV/golang-url-parser/fingerprint"
+)
+
+// BatchOptions описывает параметры headless-режима, задаваемые
+// флагами -input/-output/-format/-concurrency/-timeout.
+type BatchOptions struct {
+	InputPath   string
+	OutputPath  string
+	Format      string
+	Concurrency int
+	Timeout     time.Duration
+}
+
+// isStdinPiped сообщает, что stdin перенаправлен из файла или канала,
+// а не подключён к терминалу — это включает batch-режим без явного
+// флага -input.
+func isStdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return (info.Mode() & os.ModeCharDevice) == 0
+}
+
+// runBatch читает список URL, обрабатывает их пулом из opts.Concurrency
+// воркеров и записывает Result каждого URL в заданном формате,
+// сохраняя исходный порядок.
+func runBatch(opts BatchOptions, config Config, pagination PaginationOptions, rules *fingerprint.Engine) error {
+	urls, err := readURLs(opts.InputPath)
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if opts.OutputPath != "" {
+		file, err := os.Create(opts.OutputPath)
+		if err != nil {
+			return fmt.Errorf("не удалось создать файл вывода: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if opts.Format == "csv" {
+		writer := csv.NewWriter(out)
+		writer.Write(csvHeader())
+		writer.Flush()
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]*Result, len(urls))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				resolved, err := resolveBatchURL(urls[idx])
+				if err != nil {
+					res := newResult(urls[idx])
+					res.Error = err.Error()
+					results[idx] = res
+					continue
+				}
+				results[idx] = parseURL(resolved, config, pagination, opts.Timeout, rules)
+			}
+		}()
+	}
+
+	for idx := range urls {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, res := range results {
+		if err := writeResult(out, res, opts.Format); err != nil {
+			return fmt.Errorf("не удалось записать результат: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// resolveBatchURL приводит строку из входного файла к виду, который
+// принимает parseURL, тем же способом, что и интерактивный
+// validateURL (добавление https://, проверка доменного имени,
+// разворачивание коротких ссылок) — без интерактивных вопросов,
+// которые в headless-режиме задать некому.
+func resolveBatchURL(input string) (string, error) {
+	normalized, _, err := normalizeCandidate(input)
+	if err != nil {
+		return "", fmt.Errorf("некорректный URL: %w", err)
+	}
+
+	if normalized.Hostname() == "" || !strings.Contains(normalized.Hostname(), ".") {
+		return "", fmt.Errorf("URL должен содержать доменное имя")
+	}
+
+	return resolveShortLink(normalized), nil
+}
+
+func readURLs(path string) ([]string, error) {
+	var reader io.Reader
+	if path == "" {
+		reader = os.Stdin
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось открыть файл со списком URL: %w", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	return urls, scanner.Err()
+}
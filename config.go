@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AuthType перечисляет поддерживаемые схемы авторизации профиля.
+type AuthType string
+
+const (
+	AuthNone   AuthType = "none"
+	AuthBearer AuthType = "bearer"
+	AuthOAuth  AuthType = "oauth"
+	AuthBasic  AuthType = "basic"
+	AuthHeader AuthType = "header"
+)
+
+// AuthConfig описывает, как профиль авторизует запросы.
+type AuthConfig struct {
+	Type        AuthType `yaml:"type"`
+	Token       string   `yaml:"token"`
+	Username    string   `yaml:"username"`
+	Password    string   `yaml:"password"`
+	HeaderName  string   `yaml:"header_name"`
+	HeaderValue string   `yaml:"header_value"`
+}
+
+// RetryPolicy задаёт повторные попытки запроса для профиля.
+type RetryPolicy struct {
+	MaxRetries int           `yaml:"max_retries"`
+	Backoff    time.Duration `yaml:"backoff"`
+}
+
+// Profile — именованный набор настроек запроса (авторизация,
+// заголовки, таймаут, ретраи, прокси), применяемый к хостам,
+// подходящим под Host-глоб (например "api.wiki.*").
+type Profile struct {
+	Name    string            `yaml:"name"`
+	Host    string            `yaml:"host"`
+	Auth    AuthConfig        `yaml:"auth"`
+	Headers map[string]string `yaml:"headers"`
+	Timeout time.Duration     `yaml:"timeout"`
+	Retry   RetryPolicy       `yaml:"retry"`
+	Proxy   string            `yaml:"proxy"`
+}
+
+// Config — результат послойной загрузки конфигурации: встроенные
+// значения по умолчанию переопределяются ~/.config/url-parser/config.yaml,
+// затем переменными окружения и флагом -profile.
+type Config struct {
+	Profiles []Profile
+	Override string
+}
+
+// loadConfig собирает конфигурацию слоями: defaults → конфиг-файл в
+// домашней директории → переменные окружения. В отличие от прежней
+// версии, отсутствие переменных окружения больше не приводит к panic —
+// в этом случае используется анонимный профиль default.
+func loadConfig() Config {
+	cfg := Config{Profiles: []Profile{defaultProfile()}}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		path := filepath.Join(home, ".config", "url-parser", "config.yaml")
+		profiles, err := loadProfilesFromFile(path)
+		switch {
+		case err == nil:
+			cfg.mergeProfiles(profiles)
+		case os.IsNotExist(err):
+			// конфиг-файл необязателен
+		default:
+			log.Println("⚠️  Не удалось прочитать конфигурацию:", err)
+		}
+	}
+
+	applyEnvOverrides(&cfg)
+
+	return cfg
+}
+
+func defaultProfile() Profile {
+	return Profile{Name: "default", Host: "*", Auth: AuthConfig{Type: AuthNone}}
+}
+
+func loadProfilesFromFile(configPath string) ([]Profile, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Profiles []Profile `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("некорректный YAML в %s: %w", configPath, err)
+	}
+	return doc.Profiles, nil
+}
+
+// mergeProfiles заменяет профили с совпадающим именем и добавляет
+// новые, сохраняя порядок: более специфичные слои переопределяют
+// более общие.
+func (c *Config) mergeProfiles(profiles []Profile) {
+	for _, p := range profiles {
+		replaced := false
+		for i := range c.Profiles {
+			if c.Profiles[i].Name == p.Name {
+				c.Profiles[i] = p
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			c.Profiles = append(c.Profiles, p)
+		}
+	}
+}
+
+// applyEnvOverrides сохраняет обратную совместимость со старыми
+// переменными окружения API_AUTH_TOKEN/API_ORG_ID: если профилю
+// default не назначена авторизация явным конфиг-файлом, переменные
+// окружения применяются к нему так же, как раньше (OAuth-токен и
+// заголовок X-Org-Id).
+func applyEnvOverrides(cfg *Config) {
+	token, hasToken := os.LookupEnv("API_AUTH_TOKEN")
+	orgID, hasOrg := os.LookupEnv("API_ORG_ID")
+
+	if !hasToken && !hasOrg {
+		return
+	}
+
+	for i := range cfg.Profiles {
+		if cfg.Profiles[i].Name != "default" {
+			continue
+		}
+		if hasToken && cfg.Profiles[i].Auth.Type == AuthNone {
+			cfg.Profiles[i].Auth = AuthConfig{Type: AuthOAuth, Token: token}
+		}
+		if hasOrg {
+			if cfg.Profiles[i].Headers == nil {
+				cfg.Profiles[i].Headers = map[string]string{}
+			}
+			if _, exists := cfg.Profiles[i].Headers["X-Org-Id"]; !exists {
+				cfg.Profiles[i].Headers["X-Org-Id"] = orgID
+			}
+		}
+	}
+}
+
+// selectProfile выбирает профиль для запроса к host: явный -profile
+// override побеждает, иначе берётся первый профиль, чей Host-глоб
+// совпал, иначе default.
+func (c Config) selectProfile(host string) Profile {
+	if c.Override != "" {
+		for _, p := range c.Profiles {
+			if p.Name == c.Override {
+				return p
+			}
+		}
+	}
+
+	for _, p := range c.Profiles {
+		if p.Name == "default" || p.Host == "" {
+			continue
+		}
+		if hostGlobMatch(p.Host, host) {
+			return p
+		}
+	}
+
+	for _, p := range c.Profiles {
+		if p.Name == "default" {
+			return p
+		}
+	}
+
+	return defaultProfile()
+}
+
+func hostGlobMatch(pattern, host string) bool {
+	matched, err := path.Match(pattern, host)
+	return err == nil && matched
+}
+
+// applyAuth применяет авторизацию и дополнительные заголовки профиля
+// к запросу вместо прежнего жёстко зашитого "OAuth "-префикса и
+// X-Org-Id.
+func applyAuth(req *http.Request, profile Profile) {
+	switch profile.Auth.Type {
+	case AuthBearer:
+		if profile.Auth.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+profile.Auth.Token)
+		}
+	case AuthOAuth:
+		if profile.Auth.Token != "" {
+			req.Header.Set("Authorization", "OAuth "+profile.Auth.Token)
+		}
+	case AuthBasic:
+		if profile.Auth.Username != "" || profile.Auth.Password != "" {
+			req.SetBasicAuth(profile.Auth.Username, profile.Auth.Password)
+		}
+	case AuthHeader:
+		if profile.Auth.HeaderName != "" {
+			req.Header.Set(profile.Auth.HeaderName, profile.Auth.HeaderValue)
+		}
+	case AuthNone, "":
+		// анонимный доступ, заголовок авторизации не добавляется
+	}
+
+	for name, value := range profile.Headers {
+		req.Header.Set(name, value)
+	}
+}
+
+// newClientForProfile собирает http.Client с таймаутом и прокси
+// профиля, падая обратно на timeout по умолчанию, если профиль его не
+// задаёт.
+func newClientForProfile(profile Profile, defaultTimeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: defaultTimeout}
+	if profile.Timeout > 0 {
+		client.Timeout = profile.Timeout
+	}
+
+	if profile.Proxy != "" {
+		if proxyURL, err := url.Parse(profile.Proxy); err == nil {
+			client.Transport = &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+		}
+	}
+
+	return client
+}
+
+// doWithRetry выполняет запрос, повторяя его при сетевых ошибках
+// согласно RetryPolicy профиля.
+func doWithRetry(client *http.Client, req *http.Request, retry RetryPolicy) (*http.Response, error) {
+	attempts := retry.MaxRetries + 1
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		resp, err := client.Do(req)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+
+		if attempt < attempts-1 && retry.Backoff > 0 {
+			time.Sleep(retry.Backoff * time.Duration(attempt+1))
+		}
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NikolayBV/golang-url-parser/fingerprint"
+	"github.com/NikolayBV/golang-url-parser/urlutil"
+)
+
+// CrawlOptions описывает параметры режима -crawl.
+type CrawlOptions struct {
+	Depth         int
+	SameOrigin    bool
+	IncludeRegex  *regexp.Regexp
+	ExcludeRegex  *regexp.Regexp
+	RatePerSecond float64
+	Concurrency   int
+	Timeout       time.Duration
+	Sitemap       string
+}
+
+// parseRatePerSecond разбирает значение флага -rate вида "2/s" или
+// "30/m" в число запросов в секунду. Пустая строка означает отсутствие
+// ограничения.
+func parseRatePerSecond(raw string) (float64, error) {
+	if raw == "" {
+		return 0, nil
+	}
+
+	parts := strings.SplitN(raw, "/", 2)
+	n, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("некорректное значение -rate %q: %w", raw, err)
+	}
+	if len(parts) == 1 {
+		return n, nil
+	}
+
+	switch parts[1] {
+	case "s", "sec", "second":
+		return n, nil
+	case "m", "min", "minute":
+		return n / 60, nil
+	default:
+		return 0, fmt.Errorf("неизвестная единица измерения в -rate %q", raw)
+	}
+}
+
+// buildCrawlOptions проверяет и собирает флаги -crawl в CrawlOptions.
+func buildCrawlOptions(depth int, sameOrigin bool, includeRegex, excludeRegex, rate string, concurrency int, timeout time.Duration, sitemap string) (CrawlOptions, error) {
+	opts := CrawlOptions{
+		Depth:       depth,
+		SameOrigin:  sameOrigin,
+		Concurrency: concurrency,
+		Timeout:     timeout,
+		Sitemap:     sitemap,
+	}
+
+	if includeRegex != "" {
+		re, err := regexp.Compile(includeRegex)
+		if err != nil {
+			return opts, fmt.Errorf("некорректный -include-regex: %w", err)
+		}
+		opts.IncludeRegex = re
+	}
+	if excludeRegex != "" {
+		re, err := regexp.Compile(excludeRegex)
+		if err != nil {
+			return opts, fmt.Errorf("некорректный -exclude-regex: %w", err)
+		}
+		opts.ExcludeRegex = re
+	}
+
+	ratePerSecond, err := parseRatePerSecond(rate)
+	if err != nil {
+		return opts, err
+	}
+	opts.RatePerSecond = ratePerSecond
+
+	return opts, nil
+}
+
+type crawlJob struct {
+	url    string
+	parent string
+	depth  int
+}
+
+// runCrawl обходит ссылки начиная с seed, ограниченный по глубине,
+// и возвращает Result для каждой поставленной в очередь страницы,
+// которую разрешает robots.txt, вместе с родительским URL и глубиной
+// обнаружения. Запрещённые robots.txt страницы в результат не попадают.
+func runCrawl(seed string, config Config, pagination PaginationOptions, rules *fingerprint.Engine, opts CrawlOptions) ([]*Result, error) {
+	seedURL, err := urlutil.Normalize(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	limiter := newHostRateLimiter(opts.RatePerSecond)
+	robots := newRobotsCache()
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		visited = map[string]bool{seedURL.String(): true}
+		results []*Result
+	)
+
+	frontier := []crawlJob{{url: seedURL.String(), depth: 0}}
+
+	for len(frontier) > 0 {
+		var (
+			wg           sync.WaitGroup
+			nextFrontier []crawlJob
+		)
+		sem := make(chan struct{}, concurrency)
+
+		for _, job := range frontier {
+			wg.Add(1)
+			sem <- struct{}{}
+
+			go func(job crawlJob) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				parsed, err := url.Parse(job.url)
+				if err != nil {
+					return
+				}
+				if !robots.allowed(job.url) {
+					return
+				}
+				limiter.wait(parsed.Host)
+
+				res := parseURL(job.url, config, pagination, opts.Timeout, rules)
+				res.ParentURL = job.parent
+				res.Depth = job.depth
+
+				mu.Lock()
+				results = append(results, res)
+				mu.Unlock()
+
+				if job.depth >= opts.Depth {
+					return
+				}
+
+				for _, link := range res.Links {
+					normalized, err := urlutil.Normalize(link.URL)
+					if err != nil {
+						continue
+					}
+					key := normalized.String()
+
+					mu.Lock()
+					already := visited[key]
+					if !already {
+						visited[key] = true
+					}
+					mu.Unlock()
+					if already {
+						continue
+					}
+
+					if opts.SameOrigin && !urlutil.IsSameOrigin(normalized, seedURL) {
+						continue
+					}
+					if opts.IncludeRegex != nil && !opts.IncludeRegex.MatchString(key) {
+						continue
+					}
+					if opts.ExcludeRegex != nil && opts.ExcludeRegex.MatchString(key) {
+						continue
+					}
+
+					mu.Lock()
+					nextFrontier = append(nextFrontier, crawlJob{url: key, parent: job.url, depth: job.depth + 1})
+					mu.Unlock()
+				}
+			}(job)
+		}
+
+		wg.Wait()
+		frontier = nextFrontier
+	}
+
+	return results, nil
+}
+
+// hostRateLimiter — токен-бакет на хост (-rate): каждому хосту
+// полагается bucket ёмкостью capacity, который пополняется со
+// скоростью ratePerSecond токенов в секунду. Запрос потребляет один
+// токен; если токенов нет, горутина ждёт, пока не накопится нужное
+// количество. В отличие от жёсткого интервала между запросами, это
+// позволяет короткие всплески до capacity запросов подряд.
+type hostRateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   map[string]float64
+	updated  map[string]time.Time
+}
+
+func newHostRateLimiter(ratePerSecond float64) *hostRateLimiter {
+	capacity := ratePerSecond
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &hostRateLimiter{
+		rate:     ratePerSecond,
+		capacity: capacity,
+		tokens:   map[string]float64{},
+		updated:  map[string]time.Time{},
+	}
+}
+
+func (l *hostRateLimiter) wait(host string) {
+	if l.rate <= 0 {
+		return
+	}
+
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		tokens, seen := l.tokens[host]
+		if !seen {
+			tokens = l.capacity
+		} else if last, ok := l.updated[host]; ok {
+			tokens = math.Min(l.capacity, tokens+now.Sub(last).Seconds()*l.rate)
+		}
+		l.updated[host] = now
+
+		if tokens >= 1 {
+			l.tokens[host] = tokens - 1
+			l.mu.Unlock()
+			return
+		}
+
+		l.tokens[host] = tokens
+		wait := time.Duration((1 - tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		time.Sleep(wait)
+	}
+}
+
+// robotsCache хранит правила robots.txt по хосту, запрашивая каждый
+// хост не более одного раза за обход.
+type robotsCache struct {
+	mu     sync.Mutex
+	client *http.Client
+	rules  map[string]*robotsRules
+}
+
+func newRobotsCache() *robotsCache {
+	return &robotsCache{
+		client: &http.Client{Timeout: 10 * time.Second},
+		rules:  map[string]*robotsRules{},
+	}
+}
+
+// robotsRule — одна директива Allow/Disallow из блока "User-agent: *".
+type robotsRule struct {
+	prefix  string
+	allowed bool
+}
+
+type robotsRules struct {
+	rules []robotsRule
+}
+
+// isAllowed определяет доступность path по правилам robots.txt: из
+// всех совпавших по префиксу директив побеждает самая длинная (так же,
+// как это делают поисковые роботы), поэтому "Allow: /public/" корректно
+// перекрывает "Disallow: /".
+func (r *robotsRules) isAllowed(path string) bool {
+	if r == nil {
+		return true
+	}
+
+	allowed := true
+	longest := -1
+	for _, rule := range r.rules {
+		if rule.prefix == "" || !strings.HasPrefix(path, rule.prefix) {
+			continue
+		}
+		if len(rule.prefix) > longest {
+			longest = len(rule.prefix)
+			allowed = rule.allowed
+		}
+	}
+	return allowed
+}
+
+func (c *robotsCache) allowed(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return true
+	}
+
+	origin := u.Scheme + "://" + u.Host
+
+	c.mu.Lock()
+	rules, ok := c.rules[origin]
+	if !ok {
+		rules = c.fetch(origin)
+		c.rules[origin] = rules
+	}
+	c.mu.Unlock()
+
+	return rules.isAllowed(u.Path)
+}
+
+func (c *robotsCache) fetch(origin string) *robotsRules {
+	resp, err := c.client.Get(origin + "/robots.txt")
+	if err != nil {
+		return &robotsRules{}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &robotsRules{}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &robotsRules{}
+	}
+
+	return parseRobots(body)
+}
+
+// parseRobots разбирает директивы User-agent/Allow/Disallow блока
+// "User-agent: *" — этого достаточно, чтобы уважать большинство
+// реальных robots.txt без подключения полноценной библиотеки. Маски с
+// "*"/"$" в значении Allow/Disallow не поддерживаются — только
+// префиксное сравнение.
+func parseRobots(body []byte) *robotsRules {
+	rules := &robotsRules{}
+	appliesToUs := false
+
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+
+		switch key {
+		case "user-agent":
+			appliesToUs = value == "*"
+		case "disallow":
+			if appliesToUs && value != "" {
+				rules.rules = append(rules.rules, robotsRule{prefix: value, allowed: false})
+			}
+		case "allow":
+			if appliesToUs && value != "" {
+				rules.rules = append(rules.rules, robotsRule{prefix: value, allowed: true})
+			}
+		}
+	}
+
+	return rules
+}
+
+// writeSitemap записывает Sitemap 0.9 XML для всех успешно
+// полученных страниц (-sitemap out.xml).
+func writeSitemap(path string, results []*Result) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("не удалось создать файл sitemap: %w", err)
+	}
+	defer file.Close()
+
+	fmt.Fprintln(file, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(file, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+
+	for _, res := range results {
+		if res.Error != "" || res.Status < 200 || res.Status >= 400 {
+			continue
+		}
+
+		var escaped bytes.Buffer
+		if err := xml.EscapeText(&escaped, []byte(res.URL)); err != nil {
+			continue
+		}
+
+		fmt.Fprintln(file, "  <url>")
+		fmt.Fprintf(file, "    <loc>%s</loc>\n", escaped.String())
+		fmt.Fprintln(file, "  </url>")
+	}
+
+	fmt.Fprintln(file, `</urlset>`)
+	return nil
+}
@@ -0,0 +1,264 @@
+// Package fingerprint реализует лёгкий движок сопоставления правил,
+// похожий на appfinger: пользователь описывает признаки технологии в
+// YAML-файлах, а движок помечает ответ найденными именами.
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Matcher — одно условие внутри правила.
+type Matcher struct {
+	Part     string   `yaml:"part"`
+	Type     string   `yaml:"type"`
+	Words    []string `yaml:"words"`
+	Regex    []string `yaml:"regex"`
+	Negative bool     `yaml:"negative"`
+
+	compiled []*regexp.Regexp
+}
+
+// Rule — одно правило фингерпринтинга, может требовать, чтобы другие
+// правила уже сработали (Require), что позволяет строить
+// многоуровневое обнаружение (сначала продукт, потом версия).
+type Rule struct {
+	Name              string    `yaml:"name"`
+	MatchersCondition string    `yaml:"matchers-condition"`
+	Matchers          []Matcher `yaml:"matchers"`
+	Require           []string  `yaml:"require"`
+}
+
+// Target — данные одного ответа, по которым оцениваются правила.
+type Target struct {
+	URL     string
+	Status  int
+	Headers http.Header
+	Title   string
+	Body    string
+	JSON    interface{}
+}
+
+// Engine хранит загруженный набор правил и умеет их оценивать.
+type Engine struct {
+	rules []Rule
+}
+
+// LoadRules читает все *.yaml/*.yml файлы из dir и компилирует их
+// регулярные выражения.
+func LoadRules(dir string) (*Engine, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("fingerprint: не удалось прочитать директорию правил: %w", err)
+	}
+
+	var rules []Rule
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("fingerprint: не удалось прочитать %s: %w", name, err)
+		}
+
+		var fileRules []Rule
+		if err := yaml.Unmarshal(data, &fileRules); err != nil {
+			return nil, fmt.Errorf("fingerprint: некорректный YAML в %s: %w", name, err)
+		}
+
+		for i := range fileRules {
+			if err := compileRule(&fileRules[i]); err != nil {
+				return nil, fmt.Errorf("fingerprint: правило %q в %s: %w", fileRules[i].Name, name, err)
+			}
+		}
+		rules = append(rules, fileRules...)
+	}
+
+	return &Engine{rules: rules}, nil
+}
+
+func compileRule(rule *Rule) error {
+	for i := range rule.Matchers {
+		m := &rule.Matchers[i]
+		for _, pattern := range m.Regex {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return err
+			}
+			m.compiled = append(m.compiled, compiled)
+		}
+	}
+	return nil
+}
+
+// Evaluate прогоняет все правила по целевому ответу и возвращает
+// имена сработавших правил. Правила с Require выполняются только
+// после того, как их предпосылки уже сработали, поэтому оценка идёт
+// в несколько проходов до стабилизации.
+func (e *Engine) Evaluate(t Target) []string {
+	matched := map[string]bool{}
+	var names []string
+
+	pending := append([]Rule{}, e.rules...)
+	for {
+		progressed := false
+		var next []Rule
+
+		for _, rule := range pending {
+			if !requirementsMet(rule.Require, matched) {
+				next = append(next, rule)
+				continue
+			}
+			if ruleMatches(rule, t) {
+				if !matched[rule.Name] {
+					matched[rule.Name] = true
+					names = append(names, rule.Name)
+					progressed = true
+				}
+			}
+		}
+
+		pending = next
+		if !progressed || len(pending) == 0 {
+			break
+		}
+	}
+
+	return names
+}
+
+func requirementsMet(require []string, matched map[string]bool) bool {
+	for _, name := range require {
+		if !matched[name] {
+			return false
+		}
+	}
+	return true
+}
+
+func ruleMatches(rule Rule, t Target) bool {
+	and := !strings.EqualFold(rule.MatchersCondition, "or")
+
+	for _, m := range rule.Matchers {
+		result := matcherMatches(m, t)
+		if and && !result {
+			return false
+		}
+		if !and && result {
+			return true
+		}
+	}
+
+	return and
+}
+
+func matcherMatches(m Matcher, t Target) bool {
+	var result bool
+
+	switch m.Type {
+	case "status":
+		result = containsString(m.Words, strconv.Itoa(t.Status))
+	case "regex":
+		content := extractPart(m.Part, t)
+		for _, re := range m.compiled {
+			if re.MatchString(content) {
+				result = true
+				break
+			}
+		}
+	default: // "word"
+		content := extractPart(m.Part, t)
+		result = containsAnyWord(content, m.Words)
+	}
+
+	if m.Negative {
+		result = !result
+	}
+	return result
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, v := range haystack {
+		if v == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyWord(content string, words []string) bool {
+	for _, word := range words {
+		if strings.Contains(content, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractPart возвращает текст, по которому нужно искать совпадение,
+// для заданной части ответа. "json:<path>" обращается к вложенному
+// полю декодированного JSON через точечный путь (например
+// "json:data.version").
+func extractPart(part string, t Target) string {
+	if strings.HasPrefix(part, "json:") {
+		path := strings.TrimPrefix(part, "json:")
+		return jsonFieldAsString(t.JSON, path)
+	}
+
+	switch part {
+	case "header":
+		var b strings.Builder
+		for key, values := range t.Headers {
+			for _, value := range values {
+				b.WriteString(key)
+				b.WriteString(": ")
+				b.WriteString(value)
+				b.WriteString("\n")
+			}
+		}
+		return b.String()
+	case "title":
+		return t.Title
+	case "url":
+		return t.URL
+	default: // "body"
+		return t.Body
+	}
+}
+
+func jsonFieldAsString(data interface{}, path string) string {
+	current := data
+	for _, part := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current, ok = obj[part]
+		if !ok {
+			return ""
+		}
+	}
+
+	if s, ok := current.(string); ok {
+		return s
+	}
+	encoded, err := json.Marshal(current)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
@@ -4,23 +4,22 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/joho/godotenv"
-)
 
-// Config хранит конфигурацию из переменных окружения
-type Config struct {
-	Authorization string
-	OrgID         string
-}
+	"github.com/NikolayBV/golang-url-parser/fingerprint"
+	"github.com/NikolayBV/golang-url-parser/urlutil"
+)
 
 // PageResponse структура для ответа API
 type PageResponse struct {
@@ -39,17 +38,106 @@ func init() {
 }
 
 func main() {
-	showWelcomeMessage()
+	paginate := flag.Bool("paginate", false, "следовать по страницам API-ответа и объединять их в один JSON")
+	maxPages := flag.Int("max-pages", 10, "максимальное число страниц при --paginate (0 - без ограничения)")
+	jqSelector := flag.String("jq", "", "путь до поля с массивом для слияния, например data.items")
+
+	input := flag.String("input", "", "файл со списком URL (по одному на строку); без флага читается stdin в batch-режиме")
+	output := flag.String("output", "", "файл для записи результатов; по умолчанию stdout")
+	format := flag.String("format", "text", "формат вывода batch-режима: text, json, ndjson, csv")
+	concurrency := flag.Int("concurrency", 4, "число параллельных запросов в batch-режиме")
+	batchTimeout := flag.Duration("timeout", 30*time.Second, "таймаут запроса в batch-режиме")
+	rulesDir := flag.String("rules", "", "директория с YAML-правилами фингерпринтинга технологий")
+
+	crawl := flag.Bool("crawl", false, "режим обхода ссылок начиная с переданного в -input (или первого аргумента) URL")
+	depth := flag.Int("depth", 1, "максимальная глубина обхода в режиме -crawl")
+	sameOrigin := flag.Bool("same-origin", false, "в режиме -crawl переходить только по ссылкам того же origin")
+	includeRegex := flag.String("include-regex", "", "в режиме -crawl обходить только ссылки, подходящие под это регулярное выражение")
+	excludeRegex := flag.String("exclude-regex", "", "в режиме -crawl пропускать ссылки, подходящие под это регулярное выражение")
+	rate := flag.String("rate", "", "ограничение частоты запросов на хост в режиме -crawl, например 2/s")
+	sitemap := flag.String("sitemap", "", "файл для записи Sitemap 0.9 XML по итогам -crawl")
+
+	profileOverride := flag.String("profile", "", "имя профиля из config.yaml, принудительно используемого для всех запросов")
+	flag.Parse()
+
+	pagination := PaginationOptions{
+		Enabled:    *paginate,
+		MaxPages:   *maxPages,
+		JQSelector: *jqSelector,
+	}
+
+	var rules *fingerprint.Engine
+	if *rulesDir != "" {
+		loaded, err := fingerprint.LoadRules(*rulesDir)
+		if err != nil {
+			log.Fatal("❌ Ошибка загрузки правил фингерпринтинга:", err)
+		}
+		rules = loaded
+	}
 
-	// Загружаем конфигурацию из переменных окружения
+	// Загружаем конфигурацию слоями: defaults → config.yaml → env
 	config := loadConfig()
-	if config.Authorization == "" {
-		fmt.Println("Внимание: переменная окружения API_AUTH_TOKEN не установлена")
-		fmt.Println("Для API запросов будет использоваться анонимный доступ")
+	config.Override = *profileOverride
+
+	if *crawl {
+		seed := *input
+		if seed == "" && flag.NArg() > 0 {
+			seed = flag.Arg(0)
+		}
+		if seed == "" {
+			log.Fatal("❌ Режим -crawl требует стартовый URL: передайте его через -input или как аргумент")
+		}
+
+		opts, err := buildCrawlOptions(*depth, *sameOrigin, *includeRegex, *excludeRegex, *rate, *concurrency, *batchTimeout, *sitemap)
+		if err != nil {
+			log.Fatal("❌ Ошибка параметров -crawl:", err)
+		}
+
+		results, err := runCrawl(seed, config, pagination, rules, opts)
+		if err != nil {
+			log.Fatal("❌ Ошибка обхода:", err)
+		}
+
+		out := io.Writer(os.Stdout)
+		if *output != "" {
+			file, err := os.Create(*output)
+			if err != nil {
+				log.Fatal("❌ Не удалось создать файл вывода:", err)
+			}
+			defer file.Close()
+			out = file
+		}
+		for _, res := range results {
+			writeResult(out, res, *format)
+		}
+
+		if opts.Sitemap != "" {
+			if err := writeSitemap(opts.Sitemap, results); err != nil {
+				log.Fatal("❌ Ошибка записи sitemap:", err)
+			}
+		}
+		return
 	}
-	if config.OrgID == "" {
-		fmt.Println("Внимание: переменная окружения API_ORG_ID не установлена")
-		fmt.Println("Для некоторых API запросов может потребоваться этот заголовок")
+
+	if *input != "" || isStdinPiped() {
+		batchOpts := BatchOptions{
+			InputPath:   *input,
+			OutputPath:  *output,
+			Format:      *format,
+			Concurrency: *concurrency,
+			Timeout:     *batchTimeout,
+		}
+		if err := runBatch(batchOpts, config, pagination, rules); err != nil {
+			log.Fatal("❌ Ошибка batch-режима:", err)
+		}
+		return
+	}
+
+	showWelcomeMessage()
+
+	if def := config.selectProfile(""); def.Auth.Type == AuthNone || def.Auth.Type == "" {
+		fmt.Println("Внимание: авторизация не настроена (профиль по умолчанию анонимный)")
+		fmt.Println("Для API запросов будет использоваться анонимный доступ")
 	}
 
 	reader := bufio.NewReader(os.Stdin)
@@ -72,7 +160,8 @@ func main() {
 			continue
 		}
 
-		parseURL(validatedURL, config)
+		res := parseURL(validatedURL, config, pagination, 30*time.Second, rules)
+		writeResult(os.Stdout, res, "text")
 
 		fmt.Println("\n" + strings.Repeat("-", 50) + "\n")
 	}
@@ -80,26 +169,12 @@ func main() {
 	fmt.Println("Программа завершена. До свидания!")
 }
 
-func loadConfig() Config {
-	apiAuthToken, existAuth := os.LookupEnv("API_AUTH_TOKEN")
-	apiOrgId, existOrg := os.LookupEnv("API_ORG_ID")
-
-	if !existAuth || !existOrg {
-		panic("variables not finded!")
-	}
-
-	return Config{
-		Authorization: apiAuthToken,
-		OrgID:         apiOrgId,
-	}
-}
-
 func showWelcomeMessage() {
 	fmt.Println("=== ПАРСЕР API И ВЕБ-СТРАНИЦ ===")
 	fmt.Println("Поддерживает API Wiki и обычные веб-страницы")
-	fmt.Println("Требуемые переменные окружения:")
-	fmt.Println("  API_AUTH_TOKEN - токен авторизации (Bearer token)")
-	fmt.Println("  API_ORG_ID     - идентификатор организации")
+	fmt.Println("Авторизация настраивается в ~/.config/url-parser/config.yaml")
+	fmt.Println("(профили по хосту) либо, для обратной совместимости,")
+	fmt.Println("переменными окружения API_AUTH_TOKEN / API_ORG_ID")
 	fmt.Println()
 	fmt.Println("Доступные команды:")
 	fmt.Println("  exit, quit - выход из программы")
@@ -159,248 +234,254 @@ func showHelp() {
 	fmt.Println(strings.Repeat("-", 50))
 }
 
+// normalizeCandidate приводит ввод к net/url-ссылке, добавляя
+// https://, если схема не указана. Используется и интерактивным
+// validateURL (который в этом случае ещё спрашивает пользователя), и
+// batch-режимом, где спросить уже некого.
+func normalizeCandidate(input string) (normalized *neturl.URL, hasScheme bool, err error) {
+	hasScheme = strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://")
+
+	candidate := input
+	if !hasScheme {
+		candidate = "https://" + input
+	}
+
+	normalized, err = urlutil.Normalize(candidate)
+	return normalized, hasScheme, err
+}
+
+// resolveShortLink разворачивает normalized, если его хост — известный
+// сокращатель ссылок, иначе возвращает его как есть.
+func resolveShortLink(normalized *neturl.URL) string {
+	if !urlutil.IsShortener(normalized.Hostname()) {
+		return normalized.String()
+	}
+
+	resolved, err := urlutil.ResolveShortLink(&http.Client{Timeout: 10 * time.Second}, normalized.String())
+	if err != nil || resolved == "" {
+		return normalized.String()
+	}
+	return resolved
+}
+
 func validateURL(input string) string {
 	if input == "" {
 		fmt.Println("Ошибка: URL не может быть пустым")
 		return ""
 	}
 
-	// Для API URL всегда требуется HTTPS
-	if strings.Contains(input, "api.") && !strings.HasPrefix(input, "http") {
-		fmt.Println("API URL требует протокол HTTPS")
-		input = "https://" + input
-		fmt.Println("Используем URL:", input)
-		return input
+	normalized, hasScheme, err := normalizeCandidate(input)
+	if err != nil {
+		fmt.Println("Ошибка: некорректный URL:", err)
+		return ""
 	}
 
-	// Для обычных URL спрашиваем протокол
-	if !strings.HasPrefix(input, "http://") && !strings.HasPrefix(input, "https://") {
-		fmt.Print("Протокол не указан. Использовать https://? (y/n): ")
+	if !hasScheme {
+		if urlutil.IsAPIEndpoint(normalized) {
+			// Для API URL всегда требуется HTTPS
+			fmt.Println("API URL требует протокол HTTPS")
+			fmt.Println("Используем URL:", normalized.String())
+		} else {
+			fmt.Print("Протокол не указан. Использовать https://? (y/n): ")
 
-		reader := bufio.NewReader(os.Stdin)
-		answer, _ := reader.ReadString('\n')
-		answer = strings.TrimSpace(strings.ToLower(answer))
+			reader := bufio.NewReader(os.Stdin)
+			answer, _ := reader.ReadString('\n')
+			answer = strings.TrimSpace(strings.ToLower(answer))
 
-		if answer == "y" || answer == "yes" || answer == "да" {
-			input = "https://" + input
-			fmt.Println("Используем URL:", input)
-		} else {
-			fmt.Println("Используйте полный URL с протоколом (https://...)")
-			return ""
+			if answer == "y" || answer == "yes" || answer == "да" {
+				fmt.Println("Используем URL:", normalized.String())
+			} else {
+				fmt.Println("Используйте полный URL с протоколом (https://...)")
+				return ""
+			}
 		}
 	}
 
-	if !strings.Contains(input, ".") {
+	if normalized.Hostname() == "" || !strings.Contains(normalized.Hostname(), ".") {
 		fmt.Println("Ошибка: URL должен содержать доменное имя")
 		return ""
 	}
 
-	return input
-}
+	if urlutil.IsShortener(normalized.Hostname()) {
+		fmt.Println("🔗 Обнаружена короткая ссылка, разворачиваем...")
+		resolved := resolveShortLink(normalized)
+		if resolved != normalized.String() {
+			fmt.Println("Используем URL:", resolved)
+			return resolved
+		}
+	}
 
-func parseURL(url string, config Config) {
-	fmt.Printf("\n🔍 Парсим: %s\n", url)
-	fmt.Printf("⏰ Время: %s\n", time.Now().Format("15:04:05"))
+	return normalized.String()
+}
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+// parseURL выполняет запрос к url и возвращает унифицированный Result.
+// Результат сам парсер не печатает — вывод в нужном формате выполняет
+// вызывающий код (REPL или batch-режим) через writeResult; в log
+// попадают только сообщения о ходе постраничного обхода (--paginate).
+func parseURL(url string, config Config, pagination PaginationOptions, timeout time.Duration, rules *fingerprint.Engine) *Result {
+	res := newResult(url)
 
-	req, err := http.NewRequest("GET", url, nil)
+	parsedURL, err := neturl.Parse(url)
 	if err != nil {
-		log.Println("Ошибка создания запроса:", err)
-		return
+		res.Error = fmt.Sprintf("Ошибка создания запроса: %v", err)
+		return res
 	}
+	profile := config.selectProfile(parsedURL.Hostname())
 
-	// Устанавливаем заголовки из конфигурации
-	if config.Authorization != "" {
-		req.Header.Set("Authorization", "OAuth " + config.Authorization)
-		fmt.Println("✅ Используется Authorization заголовок")
-	}
+	client := newClientForProfile(profile, timeout)
+
+	buildRequest := func(target string) (*http.Request, error) {
+		req, err := http.NewRequest("GET", target, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	if config.OrgID != "" {
-		req.Header.Set("X-Org-Id", config.OrgID)
-		fmt.Println("✅ Используется X-Org-Id заголовок")
+		applyAuth(req, profile)
+		req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; MyParser/1.0)")
+		req.Header.Set("Accept", "application/json, text/html, */*")
+
+		return req, nil
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; MyParser/1.0)")
-	req.Header.Set("Accept", "application/json, text/html, */*")
+	req, err := buildRequest(url)
+	if err != nil {
+		res.Error = fmt.Sprintf("Ошибка создания запроса: %v", err)
+		return res
+	}
 
 	startTime := time.Now()
-	resp, err := client.Do(req)
-	requestTime := time.Since(startTime)
+	resp, err := doWithRetry(client, req, profile.Retry)
+	res.withTiming(startTime)
 
 	if err != nil {
-		log.Println("❌ Ошибка HTTP запроса:", err)
-		return
+		res.Error = fmt.Sprintf("Ошибка HTTP запроса: %v", err)
+		return res
 	}
 	defer resp.Body.Close()
 
-	fmt.Printf("📊 Статус: %d %s\n", resp.StatusCode, resp.Status)
-	fmt.Printf("⏱️  Время выполнения запроса: %v\n", requestTime)
-	fmt.Printf("📝 Content-Type: %s\n", resp.Header.Get("Content-Type"))
-	fmt.Printf("📦 Content-Length: %d байт\n", resp.ContentLength)
+	res.Status = resp.StatusCode
+	res.StatusText = resp.Status
+	res.ContentType = resp.Header.Get("Content-Type")
 
 	// Читаем весь ответ в буфер для многократного использования
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("❌ Ошибка чтения ответа:", err)
-		return
+		res.Error = fmt.Sprintf("Ошибка чтения ответа: %v", err)
+		return res
 	}
 
-	// Определяем тип контента
-	contentType := resp.Header.Get("Content-Type")
-
-	if strings.Contains(contentType, "application/json") {
-		parseJSONResponse(bodyBytes)
-	} else if strings.Contains(contentType, "text/html") {
-		parseHTMLResponse(bodyBytes, url)
+	if strings.Contains(res.ContentType, "application/json") {
+		if pagination.Enabled {
+			merged, err := followPagination(client, url, resp, bodyBytes, buildRequest, pagination)
+			if err != nil {
+				res.Error = fmt.Sprintf("Ошибка постраничного обхода: %v", err)
+			} else {
+				bodyBytes = merged
+			}
+		}
+		parseJSONResponse(bodyBytes, pagination.JQSelector, res)
+	} else if strings.Contains(res.ContentType, "text/html") {
+		parseHTMLResponse(bodyBytes, url, res)
 	} else {
-		parseGenericResponse(bodyBytes, contentType)
+		parseGenericResponse(bodyBytes, res)
 	}
-}
-
-func parseJSONResponse(body []byte) {
-	fmt.Println("\n📋 Получен JSON ответ:")
-	fmt.Println(strings.Repeat("=", 60))
 
-	// Пробуем декодировать как PageResponse
-	var page PageResponse
-	if err := json.Unmarshal(body, &page); err == nil && page.ID != 0 {
-		// Успешно распарсили как PageResponse
-		displayPageResponse(page)
-		return
+	if rules != nil {
+		res.Fingerprints = rules.Evaluate(fingerprint.Target{
+			URL:     url,
+			Status:  res.Status,
+			Headers: resp.Header,
+			Title:   res.Title,
+			Body:    string(bodyBytes),
+			JSON:    resultJSON(res),
+		})
 	}
 
-	// Пробуем как generic JSON
-	displayGenericJSON(body)
+	return res
 }
 
-func displayPageResponse(page PageResponse) {
-	fmt.Printf("🆔 ID: %d\n", page.ID)
-	fmt.Printf("🔗 Slug: %s\n", page.Slug)
-	fmt.Printf("📝 Заголовок: %s\n", page.Title)
-	fmt.Printf("📄 Тип страницы: %s\n", page.PageType)
-
-	if page.Content != "" {
-		fmt.Println("\n📖 Содержимое:")
-		fmt.Println(strings.Repeat("-", 60))
-		displayContent(page.Content)
+// resultJSON возвращает декодированное JSON-значение результата
+// (res.JSON либо res.Page, перегнанный обратно в map) для передачи
+// движку фингерпринтинга через "json:<path>" матчеры.
+func resultJSON(res *Result) interface{} {
+	if res.JSON != nil {
+		return res.JSON
 	}
-}
-
-func displayContent(content string) {
-	// Очищаем Markdown разметку для лучшего отображения
-	content = strings.ReplaceAll(content, "**", "")
-	content = strings.ReplaceAll(content, "#", "")
-	content = strings.ReplaceAll(content, "&nbsp;", " ")
-
-	lines := strings.Split(content, "\n")
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			fmt.Printf("%3d: %s\n", i+1, line)
-		}
+	if res.Page == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(res.Page)
+	if err != nil {
+		return nil
 	}
-}
-
-func displayGenericJSON(body []byte) {
 	var data interface{}
-	if err := json.Unmarshal(body, &data); err != nil {
-		fmt.Println("❌ Ошибка парсинга JSON:", err)
-		// Выводим сырой текст
-		fmt.Println("\n📄 Сырой ответ:")
-		fmt.Println(strings.Repeat("-", 60))
-		fmt.Println(string(body))
-		return
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil
 	}
+	return data
+}
 
-	// Форматируем и выводим JSON
-	formatted, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		fmt.Println("❌ Ошибка форматирования JSON:", err)
-		fmt.Println(string(body))
+// parseJSONResponse декодирует тело ответа и заполняет res.Page либо
+// res.JSON в зависимости от того, похож ли ответ на PageResponse.
+func parseJSONResponse(body []byte, jqSelector string, res *Result) {
+	// Пробуем декодировать как PageResponse
+	var page PageResponse
+	if err := json.Unmarshal(body, &page); err == nil && page.ID != 0 {
+		res.Page = &page
 		return
 	}
 
-	// Ограничиваем вывод для больших JSON
-	output := string(formatted)
-	if len(output) > 2000 {
-		fmt.Println("📄 JSON (первые 2000 символов):")
-		output = output[:2000] + "\n... [вывод сокращен]"
-	} else {
-		fmt.Println("📄 JSON:")
+	if jqSelector != "" {
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err == nil {
+			if selected := selectJQField(data, jqSelector); selected != nil {
+				if reselected, err := json.Marshal(selected); err == nil {
+					body = reselected
+				}
+			}
+		}
 	}
-	fmt.Println(strings.Repeat("-", 60))
-	fmt.Println(output)
 
-	// Если это объект, показываем ключи
-	if obj, ok := data.(map[string]interface{}); ok {
-		fmt.Println("\n🔑 Доступные поля:")
-		for key := range obj {
-			fmt.Printf("  • %s\n", key)
-		}
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		res.Raw = string(body)
+		return
 	}
+	res.JSON = data
 }
 
-func parseHTMLResponse(body []byte, baseURL string) {
+func parseHTMLResponse(body []byte, baseURL string, res *Result) {
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
-		log.Println("❌ Ошибка парсинга HTML:", err)
+		res.Error = fmt.Sprintf("Ошибка парсинга HTML: %v", err)
 		return
 	}
 
-	fmt.Println("\n🌐 HTML страница:")
-	fmt.Println(strings.Repeat("=", 60))
-	extractAndShowInfo(doc, baseURL)
+	extractPageInfo(doc, baseURL, res)
 }
 
-func parseGenericResponse(body []byte, contentType string) {
-	fmt.Printf("\n⚠️  Неизвестный тип контента: %s\n", contentType)
-	fmt.Println(strings.Repeat("-", 60))
-
-	// Ограничиваем вывод
+func parseGenericResponse(body []byte, res *Result) {
 	content := string(body)
-	contentLength := len(content)
-
-	if contentLength > 1000 {
-		fmt.Printf("📄 Предпросмотр (первые 1000 из %d символов):\n", contentLength)
-		fmt.Println(strings.Repeat("-", 40))
-		fmt.Println(content[:1000])
-		fmt.Println("\n... [вывод сокращен]")
+	if len(content) > 1000 {
+		res.Raw = content[:1000] + "\n... [вывод сокращен]"
 	} else {
-		fmt.Println("📄 Содержимое:")
-		fmt.Println(strings.Repeat("-", 40))
-		fmt.Println(content)
+		res.Raw = content
 	}
 }
 
-func extractAndShowInfo(doc *goquery.Document, baseURL string) {
-	title := strings.TrimSpace(doc.Find("title").First().Text())
-	if title == "" {
-		title = "(не найден)"
-	}
-	fmt.Printf("📄 Заголовок: %s\n", title)
+// extractPageInfo заполняет Title, Description, Links и Stats по
+// разобранному HTML-документу.
+func extractPageInfo(doc *goquery.Document, baseURL string, res *Result) {
+	res.Title = strings.TrimSpace(doc.Find("title").First().Text())
 
-	description := ""
 	doc.Find("meta[name='description']").Each(func(i int, s *goquery.Selection) {
-		if desc, exists := s.Attr("content"); exists && description == "" {
-			description = strings.TrimSpace(desc)
+		if desc, exists := s.Attr("content"); exists && res.Description == "" {
+			res.Description = strings.TrimSpace(desc)
 		}
 	})
-	if description != "" {
-		fmt.Printf("📝 Описание: %s\n", truncateText(description, 120))
-	}
 
-	fmt.Println("\n🔗 Ссылки на странице (первые 10):")
-	fmt.Println(strings.Repeat("-", 60))
-
-	linkCount := 0
+	var links []LinkInfo
 	doc.Find("a").Each(func(i int, s *goquery.Selection) {
-		if linkCount >= 10 {
-			return
-		}
-
 		text := strings.TrimSpace(s.Text())
 		href, exists := s.Attr("href")
 
@@ -419,36 +500,23 @@ func extractAndShowInfo(doc *goquery.Document, baseURL string) {
 			text = "[без текста]"
 		}
 
-		fullURL := makeAbsoluteURL(href, baseURL)
-
-		displayURL := fullURL
-		if len(displayURL) > 50 {
-			displayURL = displayURL[:47] + "..."
+		resolved, err := urlutil.Resolve(baseURL, href)
+		fullURL := href
+		if err == nil {
+			fullURL = resolved.String()
 		}
 
-		fmt.Printf("%2d. %s\n", linkCount+1, text)
-		fmt.Printf("    %s\n", displayURL)
-
-		linkCount++
+		links = append(links, LinkInfo{Text: text, URL: fullURL})
 	})
+	res.Links = links
 
-	if linkCount == 0 {
-		fmt.Println("Ссылки не найдены")
+	res.Stats = &PageStats{
+		H1:         doc.Find("h1").Length(),
+		H2:         doc.Find("h2").Length(),
+		Paragraphs: doc.Find("p").Length(),
+		Images:     doc.Find("img").Length(),
+		Links:      doc.Find("a").Length(),
 	}
-
-	// Статистика
-	fmt.Println("\n📊 Статистика:")
-	h1Count := doc.Find("h1").Length()
-	h2Count := doc.Find("h2").Length()
-	paragraphs := doc.Find("p").Length()
-	images := doc.Find("img").Length()
-	links := doc.Find("a").Length()
-
-	fmt.Printf("  • Заголовки H1: %d\n", h1Count)
-	fmt.Printf("  • Заголовки H2: %d\n", h2Count)
-	fmt.Printf("  • Параграфы: %d\n", paragraphs)
-	fmt.Printf("  • Изображения: %d\n", images)
-	fmt.Printf("  • Всего ссылок: %d\n", links)
 }
 
 func truncateText(text string, maxLength int) string {
@@ -470,38 +538,3 @@ func cleanLinkText(text string) string {
 	return text
 }
 
-func makeAbsoluteURL(href, baseURL string) string {
-	if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
-		return href
-	}
-
-	if strings.HasPrefix(href, "/") {
-		base := baseURL
-		// Убираем путь из baseURL
-		if strings.HasPrefix(base, "https://") {
-			parts := strings.SplitN(base[8:], "/", 2)
-			if len(parts) > 1 {
-				return "https://" + parts[0] + href
-			}
-			return "https://" + base[8:] + href
-		} else if strings.HasPrefix(base, "http://") {
-			parts := strings.SplitN(base[7:], "/", 2)
-			if len(parts) > 1 {
-				return "http://" + parts[0] + href
-			}
-			return "http://" + base[7:] + href
-		}
-	}
-
-	// Относительные URL
-	if strings.HasSuffix(baseURL, "/") {
-		return baseURL + href
-	}
-
-	lastSlash := strings.LastIndex(baseURL, "/")
-	if lastSlash >= 8 { // После протокола (https:// или http://)
-		return baseURL[:lastSlash+1] + href
-	}
-
-	return baseURL + "/" + href
-}
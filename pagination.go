@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PaginationOptions описывает параметры автоматического постраничного
+// обхода API-ответов, задаваемые флагами --paginate/--max-pages/--jq.
+type PaginationOptions struct {
+	Enabled  bool
+	MaxPages int
+	// JQSelector задаёт путь до поля, содержащего массив для слияния,
+	// в виде "data.items" (по аналогии с упрощённым jq-фильтром).
+	JQSelector string
+}
+
+// followPagination продолжает постраничный обход, начиная со второй
+// страницы, используя уже полученный первый ответ как основу для
+// слияния. Останавливается, когда исчерпан лимит MaxPages или
+// пропадают признаки следующей страницы, и возвращает один
+// объединённый JSON-документ.
+func followPagination(client *http.Client, firstURL string, firstResp *http.Response, firstBody []byte, buildRequest func(string) (*http.Request, error), opts PaginationOptions) ([]byte, error) {
+	var merged interface{}
+	if err := json.Unmarshal(firstBody, &merged); err != nil {
+		return nil, fmt.Errorf("страница 1: не удалось разобрать JSON: %w", err)
+	}
+
+	resp, body := firstResp, firstBody
+	currentURL := firstURL
+	page := 1
+
+	for {
+		next, ok := nextPageURL(resp, body, currentURL, page, opts.JQSelector)
+		if !ok {
+			break
+		}
+
+		page++
+		if opts.MaxPages > 0 && page > opts.MaxPages {
+			log.Printf("⚠️  Достигнут лимит страниц (--max-pages=%d), обход остановлен\n", opts.MaxPages)
+			break
+		}
+
+		log.Printf("➡️  Загружаем страницу %d: %s\n", page, next)
+
+		req, err := buildRequest(next)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err = readAndClose(resp)
+		if err != nil {
+			return nil, err
+		}
+
+		var pageData interface{}
+		if err := json.Unmarshal(body, &pageData); err != nil {
+			log.Printf("⚠️  Страница %d: не удалось разобрать JSON, обход остановлен\n", page)
+			break
+		}
+
+		merged = mergeJSON(merged, pageData)
+		currentURL = next
+	}
+
+	return json.Marshal(merged)
+}
+
+func readAndClose(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// nextPageURL определяет адрес следующей страницы, проверяя по порядку
+// заголовок Link (RFC 5988), типичные поля курсора в теле ответа и,
+// как последнюю попытку, инкремент параметра ?page=N. Если указан
+// jqSelector (--jq), страничный массив ищется не только на верхнем
+// уровне ответа, но и по вложенному пути вида "data.items" — иначе
+// обход останавливался бы после первой страницы для API, отдающих
+// массив внутри обёртки.
+func nextPageURL(resp *http.Response, body []byte, currentURL string, page int, jqSelector string) (string, bool) {
+	if link := resp.Header.Get("Link"); link != "" {
+		if next, ok := parseLinkHeaderNext(link); ok {
+			return next, true
+		}
+	}
+
+	var data map[string]interface{}
+	hasObject := json.Unmarshal(body, &data) == nil
+
+	if hasObject {
+		for _, field := range []string{"next", "next_page", "next_cursor", "nextPageToken"} {
+			value, exists := data[field]
+			if !exists || value == nil {
+				continue
+			}
+			str, ok := value.(string)
+			if !ok || str == "" {
+				continue
+			}
+			if strings.HasPrefix(str, "http://") || strings.HasPrefix(str, "https://") {
+				return str, true
+			}
+			return withQueryParam(currentURL, "cursor", str), true
+		}
+
+		if jqSelector != "" {
+			if selected, ok := selectJQField(data, jqSelector).([]interface{}); ok && len(selected) > 0 {
+				return withQueryParam(currentURL, "page", strconv.Itoa(page+1)), true
+			}
+		}
+	}
+
+	var arr []interface{}
+	if err := json.Unmarshal(body, &arr); err == nil && len(arr) > 0 {
+		return withQueryParam(currentURL, "page", strconv.Itoa(page+1)), true
+	}
+
+	return "", false
+}
+
+// parseLinkHeaderNext ищет rel="next" в заголовке Link вида
+// `<https://...>; rel="next", <https://...>; rel="prev"`.
+func parseLinkHeaderNext(header string) (string, bool) {
+	for _, part := range strings.Split(header, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+
+		rawURL := strings.TrimSpace(segments[0])
+		rawURL = strings.TrimPrefix(rawURL, "<")
+		rawURL = strings.TrimSuffix(rawURL, ">")
+
+		for _, attr := range segments[1:] {
+			attr = strings.TrimSpace(attr)
+			if attr == `rel="next"` || attr == "rel=next" {
+				return rawURL, true
+			}
+		}
+	}
+	return "", false
+}
+
+// withQueryParam добавляет или заменяет параметр запроса в URL, не
+// затрагивая остальную часть адреса.
+func withQueryParam(rawURL, key, value string) string {
+	base := rawURL
+	query := ""
+	if idx := strings.Index(rawURL, "?"); idx >= 0 {
+		base = rawURL[:idx]
+		query = rawURL[idx+1:]
+	}
+
+	pairs := []string{}
+	found := false
+	if query != "" {
+		for _, pair := range strings.Split(query, "&") {
+			if strings.HasPrefix(pair, key+"=") {
+				pairs = append(pairs, key+"="+value)
+				found = true
+			} else if pair != "" {
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+	if !found {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	return base + "?" + strings.Join(pairs, "&")
+}
+
+// mergeJSON объединяет два декодированных JSON-значения по правилам:
+// массивы конкатенируются, объекты сливаются по ключам (с рекурсивным
+// слиянием значений), а скаляры из последующей страницы перекрывают
+// предыдущие.
+func mergeJSON(a, b interface{}) interface{} {
+	switch left := a.(type) {
+	case []interface{}:
+		if right, ok := b.([]interface{}); ok {
+			return append(append([]interface{}{}, left...), right...)
+		}
+		return b
+	case map[string]interface{}:
+		right, ok := b.(map[string]interface{})
+		if !ok {
+			return b
+		}
+		merged := make(map[string]interface{}, len(left))
+		for k, v := range left {
+			merged[k] = v
+		}
+		for k, v := range right {
+			if existing, ok := merged[k]; ok {
+				merged[k] = mergeJSON(existing, v)
+			} else {
+				merged[k] = v
+			}
+		}
+		return merged
+	default:
+		return b
+	}
+}
+
+// selectJQField выбирает вложенное значение по упрощённому jq-пути
+// ("data.items"), используемому флагом --jq для указания, какое поле
+// хранит массив, подлежащий слиянию.
+func selectJQField(data interface{}, selector string) interface{} {
+	if selector == "" {
+		return data
+	}
+
+	current := data
+	for _, part := range strings.Split(selector, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current, ok = obj[part]
+		if !ok {
+			return nil
+		}
+	}
+	return current
+}
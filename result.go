@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LinkInfo описывает одну ссылку, найденную на HTML-странице.
+type LinkInfo struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// PageStats содержит базовую статистику по HTML-документу.
+type PageStats struct {
+	H1         int `json:"h1"`
+	H2         int `json:"h2"`
+	Paragraphs int `json:"paragraphs"`
+	Images     int `json:"images"`
+	Links      int `json:"links"`
+}
+
+// Result — унифицированный результат обработки одного URL, который
+// используется как интерактивным режимом, так и batch-режимом. Для
+// JSON- и прочих не-HTML ответов заполняется одно из полей
+// Page/JSON/Raw; для HTML-ответа вместо них заполняются
+// Title/Description/Links/Stats, а при ошибке — только Error.
+type Result struct {
+	URL          string        `json:"url"`
+	Status       int           `json:"status,omitempty"`
+	StatusText   string        `json:"status_text,omitempty"`
+	ContentType  string        `json:"content_type,omitempty"`
+	DurationMS   int64         `json:"duration_ms,omitempty"`
+	Title        string        `json:"title,omitempty"`
+	Description  string        `json:"description,omitempty"`
+	Links        []LinkInfo    `json:"links,omitempty"`
+	Stats        *PageStats    `json:"stats,omitempty"`
+	Page         *PageResponse `json:"page,omitempty"`
+	JSON         interface{}   `json:"json,omitempty"`
+	Raw          string        `json:"raw,omitempty"`
+	Fingerprints []string      `json:"fingerprints,omitempty"`
+	ParentURL    string        `json:"parent_url,omitempty"`
+	Depth        int           `json:"depth,omitempty"`
+	Error        string        `json:"error,omitempty"`
+}
+
+func newResult(url string) *Result {
+	return &Result{URL: url}
+}
+
+func (r *Result) withTiming(start time.Time) {
+	r.DurationMS = time.Since(start).Milliseconds()
+}
+
+// writeResult сериализует Result в заданном формате и пишет его в w.
+// Формат "text" воспроизводит исходный человекочитаемый вывод
+// программы, остальные предназначены для скриптового использования.
+func writeResult(w io.Writer, res *Result, format string) error {
+	switch format {
+	case "json":
+		encoded, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(encoded))
+		return err
+	case "ndjson":
+		encoded, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(encoded))
+		return err
+	case "csv":
+		return writeResultCSV(w, res)
+	default:
+		_, err := fmt.Fprint(w, formatResultText(res))
+		return err
+	}
+}
+
+func writeResultCSV(w io.Writer, res *Result) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	title := res.Title
+	if res.Page != nil {
+		title = res.Page.Title
+	}
+
+	record := []string{
+		res.URL,
+		strconv.Itoa(res.Status),
+		res.ContentType,
+		strconv.FormatInt(res.DurationMS, 10),
+		title,
+		res.Description,
+		strconv.Itoa(len(res.Links)),
+		strings.Join(res.Fingerprints, "|"),
+		res.ParentURL,
+		strconv.Itoa(res.Depth),
+		res.Error,
+	}
+	return writer.Write(record)
+}
+
+// csvHeader возвращает заголовок для формата csv; вызывается один раз
+// перед первой записью в batch-режиме.
+func csvHeader() []string {
+	return []string{"url", "status", "content_type", "duration_ms", "title", "description", "links", "fingerprints", "parent_url", "depth", "error"}
+}
+
+// formatResultText воспроизводит исходный emoji-вывод программы на
+// основе заполненного Result.
+func formatResultText(res *Result) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\n🔍 Парсим: %s\n", res.URL)
+
+	if res.Error != "" {
+		fmt.Fprintf(&b, "❌ %s\n", res.Error)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "📊 Статус: %d %s\n", res.Status, res.StatusText)
+	fmt.Fprintf(&b, "⏱️  Время выполнения запроса: %s\n", time.Duration(res.DurationMS)*time.Millisecond)
+	fmt.Fprintf(&b, "📝 Content-Type: %s\n", res.ContentType)
+
+	switch {
+	case res.Page != nil:
+		fmt.Fprintln(&b, "\n📋 Получен JSON ответ:")
+		fmt.Fprintln(&b, strings.Repeat("=", 60))
+		fmt.Fprintf(&b, "🆔 ID: %d\n", res.Page.ID)
+		fmt.Fprintf(&b, "🔗 Slug: %s\n", res.Page.Slug)
+		fmt.Fprintf(&b, "📝 Заголовок: %s\n", res.Page.Title)
+		fmt.Fprintf(&b, "📄 Тип страницы: %s\n", res.Page.PageType)
+		if res.Page.Content != "" {
+			fmt.Fprintln(&b, "\n📖 Содержимое:")
+			fmt.Fprintln(&b, strings.Repeat("-", 60))
+			fmt.Fprint(&b, formatContentText(res.Page.Content))
+		}
+	case res.JSON != nil:
+		fmt.Fprintln(&b, "\n📋 Получен JSON ответ:")
+		fmt.Fprintln(&b, strings.Repeat("=", 60))
+		fmt.Fprint(&b, formatGenericJSONText(res.JSON))
+	case res.Title != "" || res.Description != "" || len(res.Links) > 0 || res.Stats != nil:
+		fmt.Fprintln(&b, "\n🌐 HTML страница:")
+		fmt.Fprintln(&b, strings.Repeat("=", 60))
+		fmt.Fprint(&b, formatHTMLInfoText(res))
+	case res.Raw != "":
+		fmt.Fprintf(&b, "📦 Content-Length: %d байт\n", len(res.Raw))
+		fmt.Fprintln(&b, strings.Repeat("-", 60))
+		fmt.Fprintln(&b, res.Raw)
+	}
+
+	if len(res.Fingerprints) > 0 {
+		fmt.Fprintln(&b, "\n🧩 Обнаруженные технологии:")
+		for _, name := range res.Fingerprints {
+			fmt.Fprintf(&b, "  • %s\n", name)
+		}
+	}
+
+	return b.String()
+}
+
+func formatContentText(content string) string {
+	content = strings.ReplaceAll(content, "**", "")
+	content = strings.ReplaceAll(content, "#", "")
+	content = strings.ReplaceAll(content, "&nbsp;", " ")
+
+	var b strings.Builder
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			fmt.Fprintf(&b, "%3d: %s\n", i+1, line)
+		}
+	}
+	return b.String()
+}
+
+func formatGenericJSONText(data interface{}) string {
+	var b strings.Builder
+
+	formatted, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		fmt.Fprintln(&b, "❌ Ошибка форматирования JSON:", err)
+		return b.String()
+	}
+
+	output := string(formatted)
+	if len(output) > 2000 {
+		fmt.Fprintln(&b, "📄 JSON (первые 2000 символов):")
+		output = output[:2000] + "\n... [вывод сокращен]"
+	} else {
+		fmt.Fprintln(&b, "📄 JSON:")
+	}
+	fmt.Fprintln(&b, strings.Repeat("-", 60))
+	fmt.Fprintln(&b, output)
+
+	if obj, ok := data.(map[string]interface{}); ok {
+		fmt.Fprintln(&b, "\n🔑 Доступные поля:")
+		for key := range obj {
+			fmt.Fprintf(&b, "  • %s\n", key)
+		}
+	}
+
+	return b.String()
+}
+
+func formatHTMLInfoText(res *Result) string {
+	var b strings.Builder
+
+	title := res.Title
+	if title == "" {
+		title = "(не найден)"
+	}
+	fmt.Fprintf(&b, "📄 Заголовок: %s\n", title)
+
+	if res.Description != "" {
+		fmt.Fprintf(&b, "📝 Описание: %s\n", truncateText(res.Description, 120))
+	}
+
+	fmt.Fprintln(&b, "\n🔗 Ссылки на странице (первые 10):")
+	fmt.Fprintln(&b, strings.Repeat("-", 60))
+
+	if len(res.Links) == 0 {
+		fmt.Fprintln(&b, "Ссылки не найдены")
+	}
+	displayLinks := res.Links
+	if len(displayLinks) > 10 {
+		displayLinks = displayLinks[:10]
+	}
+	for i, link := range displayLinks {
+		displayURL := link.URL
+		if len(displayURL) > 50 {
+			displayURL = displayURL[:47] + "..."
+		}
+		fmt.Fprintf(&b, "%2d. %s\n", i+1, link.Text)
+		fmt.Fprintf(&b, "    %s\n", displayURL)
+	}
+
+	if res.Stats != nil {
+		fmt.Fprintln(&b, "\n📊 Статистика:")
+		fmt.Fprintf(&b, "  • Заголовки H1: %d\n", res.Stats.H1)
+		fmt.Fprintf(&b, "  • Заголовки H2: %d\n", res.Stats.H2)
+		fmt.Fprintf(&b, "  • Параграфы: %d\n", res.Stats.Paragraphs)
+		fmt.Fprintf(&b, "  • Изображения: %d\n", res.Stats.Images)
+		fmt.Fprintf(&b, "  • Всего ссылок: %d\n", res.Stats.Links)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,181 @@
+// Package urlutil предоставляет нормализацию, разрешение ссылок и
+// классификацию URL поверх net/url, заменяя ручную строковую работу
+// с адресами, которая раньше была разбросана по main.go.
+package urlutil
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Normalize приводит сырую строку URL к каноническому виду: схема и
+// хост переводятся в нижний регистр, путь резолвится относительно
+// "." и "..", стандартные порты (80 для http, 443 для https)
+// удаляются, а параметры запроса сортируются по ключу.
+func Normalize(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("urlutil: не удалось разобрать URL %q: %w", raw, err)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Host = stripDefaultPort(u.Scheme, u.Host)
+
+	if u.Path != "" {
+		u.Path = path.Clean(u.Path)
+		if !strings.HasPrefix(u.Path, "/") {
+			u.Path = "/" + u.Path
+		}
+	}
+
+	if u.RawQuery != "" {
+		u.RawQuery = sortQuery(u.RawQuery)
+	}
+
+	return u, nil
+}
+
+func stripDefaultPort(scheme, host string) string {
+	h, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return host
+	}
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		return h
+	}
+	return host
+}
+
+// sortQuery переупорядочивает пары запроса по ключу, чтобы два URL,
+// отличающихся только порядком параметров, нормализовались одинаково.
+func sortQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sorted := url.Values{}
+	for _, k := range keys {
+		sorted[k] = values[k]
+	}
+	return sorted.Encode()
+}
+
+// Resolve абсолютизирует href относительно base по правилам RFC 3986.
+func Resolve(base, href string) (*url.URL, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("urlutil: некорректный базовый URL %q: %w", base, err)
+	}
+
+	ref, err := url.Parse(href)
+	if err != nil {
+		return nil, fmt.Errorf("urlutil: некорректная ссылка %q: %w", href, err)
+	}
+
+	return baseURL.ResolveReference(ref), nil
+}
+
+// IsAPIEndpoint определяет, похож ли URL на обращение к API: хост
+// содержит поддомен "api" либо путь начинается с "/api".
+func IsAPIEndpoint(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	if host == "api" || strings.HasPrefix(host, "api.") || strings.Contains(host, ".api.") {
+		return true
+	}
+
+	lowerPath := strings.ToLower(u.Path)
+	return lowerPath == "/api" || strings.HasPrefix(lowerPath, "/api/")
+}
+
+// IsSameOrigin сравнивает схему и хост (включая порт) двух URL.
+func IsSameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+var trackingParams = map[string]bool{
+	"utm_source":   true,
+	"utm_medium":   true,
+	"utm_campaign": true,
+	"utm_term":     true,
+	"utm_content":  true,
+	"gclid":        true,
+	"fbclid":       true,
+	"yclid":        true,
+	"ysclid":       true,
+	"_openstat":    true,
+}
+
+// IsTrackingParam сообщает, является ли имя параметра запроса
+// известным маркетинговым/аналитическим трекером (utm_*, gclid и т.п.).
+func IsTrackingParam(name string) bool {
+	return trackingParams[strings.ToLower(name)]
+}
+
+var shortenerHosts = map[string]bool{
+	"t.co":        true,
+	"bit.ly":      true,
+	"goo.gl":      true,
+	"tinyurl.com": true,
+}
+
+// IsShortener сообщает, является ли хост известным сервисом коротких
+// ссылок.
+func IsShortener(host string) bool {
+	return shortenerHosts[strings.ToLower(host)]
+}
+
+// ResolveShortLink разворачивает короткую ссылку, переходя ровно по
+// одному редиректу без загрузки тела ответа. Если хост не входит в
+// список известных сокращателей либо редирект отсутствует, возвращает
+// исходный адрес без изменений.
+func ResolveShortLink(client *http.Client, raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw, err
+	}
+	if !IsShortener(u.Hostname()) {
+		return raw, nil
+	}
+
+	req, err := http.NewRequest(http.MethodHead, raw, nil)
+	if err != nil {
+		return raw, err
+	}
+
+	noRedirect := &http.Client{
+		Timeout: client.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := noRedirect.Do(req)
+	if err != nil {
+		return raw, err
+	}
+	defer resp.Body.Close()
+
+	loc := resp.Header.Get("Location")
+	if loc == "" {
+		return raw, nil
+	}
+
+	resolved, err := Resolve(raw, loc)
+	if err != nil {
+		return raw, nil
+	}
+	return resolved.String(), nil
+}